@@ -0,0 +1,296 @@
+package testdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// NewMySQL initialises a new MySQL test database at the database indicated
+// by dsn. dsn must be a valid connection that has permission to create new
+// databases. Returns the Db handle representing a fully migrated, isolated
+// database ready for use in your test.
+//
+// provide a nil migrator to disable any migrations and return a blank database
+// instead.
+func NewMySQL(t testing.TB, dsn string, migrator Migrator) Db {
+	return New[*sql.DB](t, dsn, &mysqlInitializer{}, migrator)
+}
+
+// NewMySQLWithSeed is NewMySQL, but also pre-populates the template database
+// with s once it's been migrated. See Seeder.
+func NewMySQLWithSeed(t testing.TB, dsn string, migrator Migrator, s Seeder) Db {
+	return NewWithSeed[*sql.DB](t, dsn, &mysqlInitializer{}, migrator, s)
+}
+
+// MySQLDb shares its Insert/QueryValue/QueryRow/Exec implementation with
+// every other database/sql-backed Db via the embedded sqlDb; only Drop
+// needs MySQL-specific handling, to kill connections before the DROP
+// DATABASE mirroring the pg_terminate_backend logic in PgDb.Drop.
+type MySQLDb struct {
+	*sqlDb
+}
+
+func (m *MySQLDb) Drop(t testing.TB) {
+	m.mu.Lock()
+	if m.db != nil {
+		_ = m.db.Close()
+		m.db = nil
+	}
+	m.mu.Unlock()
+
+	root, err := sql.Open("mysql", m.rootDsn)
+	must(t, err)
+	defer root.Close()
+
+	rows, err := root.QueryContext(context.Background(),
+		"SELECT id FROM information_schema.processlist WHERE db = ?", m.name)
+	must(t, err)
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		must(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	must(t, rows.Err())
+	_ = rows.Close()
+
+	for _, id := range ids {
+		_, _ = root.ExecContext(context.Background(), fmt.Sprintf("KILL %d", id))
+	}
+
+	_, err = root.ExecContext(context.Background(), fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", verifyMySQLDbName(t, m.name)))
+	must(t, err)
+}
+
+// mysqlTableDump is a snapshot of one table in a template database: the DDL
+// to recreate it, plus every row it held, so replaying a dump reproduces
+// both the schema and any fixture data a Seeder (see seed.go) inserted.
+type mysqlTableDump struct {
+	name string
+	ddl  string
+	cols []string
+	rows [][]any
+}
+
+// mysqlSchemaCache holds the dump captured from each template database,
+// keyed by templateName, so CreateFromTemplate only has to dump a template
+// once per process. MySQL has no CREATE DATABASE ... TEMPLATE, so this is
+// how we approximate Postgres' copy-on-create semantics.
+var mysqlSchemaCache sync.Map // map[string][]mysqlTableDump
+
+// mysqlInitializer implements Initializer[*sql.DB] against
+// github.com/go-sql-driver/mysql.
+type mysqlInitializer struct {
+	dsn string
+
+	mu    sync.Mutex
+	locks map[string]*sql.Conn
+}
+
+func (m *mysqlInitializer) Connect(t testing.TB, dsn string) *sql.DB {
+	m.dsn = dsn
+
+	db, err := sql.Open("mysql", dsn)
+	must(t, err)
+	return db
+}
+
+func (m *mysqlInitializer) Lock(t testing.TB, conn *sql.DB, name string) {
+	// GET_LOCK is session-scoped, so we must hold a single connection out of
+	// the pool for the lifetime of the lock rather than letting database/sql
+	// hand Unlock a different one.
+	c, err := conn.Conn(context.Background())
+	must(t, err)
+
+	var got int
+	err = c.QueryRowContext(context.Background(), "SELECT GET_LOCK(?, -1)", name).Scan(&got)
+	must(t, err)
+
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*sql.Conn)
+	}
+	m.locks[name] = c
+	m.mu.Unlock()
+}
+
+func (m *mysqlInitializer) Unlock(t testing.TB, conn *sql.DB, name string) {
+	m.mu.Lock()
+	c, ok := m.locks[name]
+	delete(m.locks, name)
+	m.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	_, err := c.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", name)
+	must(t, err)
+	_ = c.Close()
+}
+
+func (m *mysqlInitializer) Exists(t testing.TB, conn *sql.DB, name string) bool {
+	row := conn.QueryRowContext(context.Background(),
+		"SELECT SCHEMA_NAME FROM information_schema.schemata WHERE SCHEMA_NAME = ?", name)
+
+	var got string
+	err := row.Scan(&got)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+	must(t, err)
+
+	return true
+}
+
+func (m *mysqlInitializer) Create(t testing.TB, conn *sql.DB, name string) {
+	_, err := conn.ExecContext(context.Background(), fmt.Sprintf("CREATE DATABASE `%s`", verifyMySQLDbName(t, name)))
+	must(t, err)
+}
+
+func (m *mysqlInitializer) CreateFromTemplate(t testing.TB, conn *sql.DB, template, name string) {
+	dump := m.dumpFor(t, conn, template)
+
+	m.Create(t, conn, name)
+
+	targetDsn := m.NewDsn(t, m.dsn, name)
+	target, err := sql.Open("mysql", targetDsn)
+	must(t, err)
+	defer target.Close()
+
+	for _, table := range dump {
+		_, err := target.ExecContext(context.Background(), table.ddl)
+		must(t, err)
+
+		if len(table.rows) == 0 {
+			continue
+		}
+
+		cols := make([]string, len(table.cols))
+		placeholders := make([]string, len(table.cols))
+		for i, col := range table.cols {
+			cols[i] = fmt.Sprintf("`%s`", col)
+			placeholders[i] = "?"
+		}
+
+		insert := fmt.Sprintf(
+			"INSERT INTO `%s`(%s) VALUES(%s)",
+			table.name,
+			strings.Join(cols, ","),
+			strings.Join(placeholders, ","),
+		)
+
+		for _, row := range table.rows {
+			_, err := target.ExecContext(context.Background(), insert, row...)
+			must(t, err)
+		}
+	}
+}
+
+// dumpFor returns the cached schema+row dump for template, capturing and
+// caching it from conn on first use.
+func (m *mysqlInitializer) dumpFor(t testing.TB, conn *sql.DB, template string) []mysqlTableDump {
+	if cached, ok := mysqlSchemaCache.Load(template); ok {
+		return cached.([]mysqlTableDump)
+	}
+
+	rows, err := conn.QueryContext(context.Background(),
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = ?", template)
+	must(t, err)
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		must(t, rows.Scan(&name))
+		tables = append(tables, name)
+	}
+	must(t, rows.Err())
+	_ = rows.Close()
+
+	dump := make([]mysqlTableDump, 0, len(tables))
+	for _, table := range tables {
+		dump = append(dump, m.dumpTable(t, conn, template, table))
+	}
+
+	mysqlSchemaCache.Store(template, dump)
+	return dump
+}
+
+// dumpTable captures both the DDL and the row data of template.table, so
+// any rows a Seeder inserted into the template (see seed.go) before it's
+// snapshotted are reproduced in every test database too, not just the
+// schema.
+func (m *mysqlInitializer) dumpTable(t testing.TB, conn *sql.DB, template, table string) mysqlTableDump {
+	var tbl, create string
+	row := conn.QueryRowContext(context.Background(),
+		fmt.Sprintf("SHOW CREATE TABLE `%s`.`%s`", template, table))
+	must(t, row.Scan(&tbl, &create))
+
+	rows, err := conn.QueryContext(context.Background(), fmt.Sprintf("SELECT * FROM `%s`.`%s`", template, table))
+	must(t, err)
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	must(t, err)
+
+	var data [][]any
+	for rows.Next() {
+		vals := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+
+		must(t, rows.Scan(ptrs...))
+		data = append(data, vals)
+	}
+	must(t, rows.Err())
+
+	return mysqlTableDump{name: table, ddl: create, cols: cols, rows: data}
+}
+
+func (m *mysqlInitializer) NewDsn(t testing.TB, base string, newName string) string {
+	cfg, err := mysql.ParseDSN(base)
+	must(t, err)
+
+	cfg.DBName = newName
+
+	return cfg.FormatDSN()
+}
+
+func (m *mysqlInitializer) NewDb(t testing.TB, rootDsn, dsn string) Db {
+	cfg, err := mysql.ParseDSN(dsn)
+	must(t, err)
+
+	return &MySQLDb{&sqlDb{
+		name:    cfg.DBName,
+		dsn:     dsn,
+		rootDsn: rootDsn,
+		driver:  "mysql",
+		dialect: MySQLDialect,
+	}}
+}
+
+func (m *mysqlInitializer) Remove(t testing.TB, conn *sql.DB, name string) {
+	_, err := conn.ExecContext(context.Background(), fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", verifyMySQLDbName(t, name)))
+	must(t, err)
+}
+
+var mysqlDbNameRegex = regexp.MustCompile("^[a-zA-Z0-9_]+$")
+
+func verifyMySQLDbName(t testing.TB, name string) string {
+	if !mysqlDbNameRegex.MatchString(name) {
+		ErrorHandler(t, fmt.Errorf("%s as a DB name may be unsafe. letters, numbers and _ only", name))
+	}
+
+	return name
+}
@@ -0,0 +1,144 @@
+package testdb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// MigrateMigrator implements a migration strategy using golang-migrate
+// in-process against Postgres (via the pgx/v5 database driver), rather than
+// shelling out to the migrate CLI. Unlike CliMigrator, this has no
+// dependency on bash, md5sum or an external migrate binary, so it works the
+// same on Windows and in minimal CI containers.
+//
+// src may be a file source (source/file) or an embed.FS wrapped with
+// iofs.New (source/iofs); either is read in version order to build both the
+// hash and the migrations that get applied.
+func MigrateMigrator(t testing.TB, src source.Driver, opts ...Option) Migrator {
+	m := &nativeMigrator{source: src, migrationsTable: "schema_migrations"}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Option configures a Migrator returned by MigrateMigrator.
+type Option func(*nativeMigrator)
+
+// WithMultiStatement enables the pgx driver's x-multi-statement option,
+// allowing more than one SQL statement per migration file.
+func WithMultiStatement() Option {
+	return func(m *nativeMigrator) {
+		m.multiStatement = true
+	}
+}
+
+// WithMigrationsTable overrides the name of the table golang-migrate uses to
+// track applied migrations. Defaults to "schema_migrations".
+func WithMigrationsTable(name string) Option {
+	return func(m *nativeMigrator) {
+		m.migrationsTable = name
+	}
+}
+
+// WithStatementTimeout bounds how long any single migration statement may
+// run, forwarded to the pgx driver as its statement_timeout DSN param.
+func WithStatementTimeout(d time.Duration) Option {
+	return func(m *nativeMigrator) {
+		m.statementTimeout = d
+	}
+}
+
+// nativeMigrator is created by MigrateMigrator.
+type nativeMigrator struct {
+	source           source.Driver
+	multiStatement   bool
+	migrationsTable  string
+	statementTimeout time.Duration
+}
+
+func (m *nativeMigrator) Hash(t testing.TB) string {
+	h := sha256.New()
+
+	version, err := m.source.First()
+	if errors.Is(err, os.ErrNotExist) {
+		return hex.EncodeToString(h.Sum(nil))
+	}
+	must(t, err)
+
+	for {
+		r, identifier, err := m.source.ReadUp(version)
+		must(t, err)
+
+		_, _ = fmt.Fprintf(h, "%d_%s\n", version, identifier)
+
+		_, err = io.Copy(h, r)
+		_ = r.Close()
+		must(t, err)
+
+		version, err = m.source.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		must(t, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (m *nativeMigrator) Migrate(t testing.TB, dsn string) {
+	mig, err := migrate.NewWithSourceInstance("testdb", m.source, m.pgxDsn(dsn))
+	must(t, err)
+
+	defer func() {
+		_, _ = mig.Close()
+	}()
+
+	err = mig.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		must(t, err)
+	}
+}
+
+// pgxDsn forwards our options onto dsn as query params understood by
+// golang-migrate's pgx/v5 database driver.
+func (m *nativeMigrator) pgxDsn(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		// dsn is validated elsewhere; fall back to passing it through as-is.
+		return dsn
+	}
+
+	// golang-migrate's pgx/v5 database driver registers itself under the
+	// "pgx5" scheme rather than "postgres"/"postgresql".
+	u.Scheme = "pgx5"
+
+	q := u.Query()
+	if m.multiStatement {
+		q.Set("x-multi-statement", "true")
+	}
+	if m.migrationsTable != "" {
+		q.Set("x-migrations-table", m.migrationsTable)
+	}
+	if m.statementTimeout > 0 {
+		q.Set("statement_timeout", fmt.Sprintf("%d", m.statementTimeout.Milliseconds()))
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
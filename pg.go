@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"hash/crc32"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -22,11 +25,105 @@ func NewPg(t testing.TB, dsn string, migrator Migrator) Db {
 	return New[*pgx.Conn](t, dsn, &pgInitializer{}, migrator)
 }
 
+// NewPgWithSeed is NewPg, but also pre-populates the template database with
+// s once it's been migrated. See Seeder.
+func NewPgWithSeed(t testing.TB, dsn string, migrator Migrator, s Seeder) Db {
+	return NewWithSeed[*pgx.Conn](t, dsn, &pgInitializer{}, migrator, s)
+}
+
 type PgDb struct {
 	name    string
 	dsn     string
 	rootDsn string
-	conns   map[string]*pgx.Conn
+
+	poolMu sync.Mutex
+	pool   *pgxpool.Pool
+
+	// tx and txConn are set when this Db was created by NewPgTx; when tx is
+	// non-nil all operations run inside it instead of against a dedicated,
+	// per-test database.
+	tx     pgx.Tx
+	txConn *pgx.Conn
+}
+
+// pgQueryer is satisfied by *pgx.Conn, pgx.Tx and *pgxpool.Pool, letting
+// PgDb route its operations through whichever one is active for this test.
+type pgQueryer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (p *PgDb) queryer(t testing.TB) pgQueryer {
+	if p.tx != nil {
+		return p.tx
+	}
+
+	return p.Pool(t)
+}
+
+// Pool returns a pgxpool.Pool connected to this test database, creating it
+// on first use. Insert/QueryValue/QueryRow/Exec acquire from this pool
+// rather than a single cached connection, so t.Parallel() subtests sharing
+// one Db don't serialize (or deadlock) on it. The pool is closed in Drop.
+//
+// Pool is called from every query method, including by concurrent
+// t.Parallel() subtests sharing this Db, so creation is guarded by poolMu
+// to avoid both a data race on p.pool and leaking whichever pool loses the
+// race.
+//
+// db must not have been created by NewPgTx: such a Db has no dedicated test
+// database of its own (it runs inside a shared, long-lived one via tx), so
+// connecting a real pool to p.dsn would open connections directly against
+// that shared database, bypassing the transaction entirely.
+func (p *PgDb) Pool(t testing.TB) *pgxpool.Pool {
+	if p.tx != nil {
+		ErrorHandler(t, fmt.Errorf("testdb: Pool doesn't support a Db created by NewPgTx; use Savepoint, or run statements against db's existing transaction directly"))
+		return nil
+	}
+
+	p.poolMu.Lock()
+	defer p.poolMu.Unlock()
+
+	if p.pool == nil {
+		pool, err := pgxpool.Connect(context.Background(), p.dsn)
+		must(t, err)
+		p.pool = pool
+	}
+
+	return p.pool
+}
+
+// WithTx runs fn against a transaction acquired from db's pool, for testing
+// code paths that need to manage their own transaction. The transaction is
+// always rolled back once fn returns, and is acquired/released independently
+// of the connection(s) testdb itself uses, so it won't clash with or be
+// serialized behind them. db must have been created by NewPg (or another
+// constructor backed by PgDb), but not by NewPgTx.
+func WithTx(t testing.TB, db Db, fn func(pgx.Tx)) {
+	t.Helper()
+
+	p, ok := db.(*PgDb)
+	if !ok {
+		ErrorHandler(t, fmt.Errorf("testdb: WithTx requires a Db backed by PgDb"))
+		return
+	}
+
+	if p.tx != nil {
+		ErrorHandler(t, fmt.Errorf("testdb: WithTx doesn't support a Db created by NewPgTx; use Savepoint, or run fn's statements against db's existing transaction directly"))
+		return
+	}
+
+	conn, err := p.Pool(t).Acquire(context.Background())
+	must(t, err)
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	must(t, err)
+	defer func() {
+		_ = tx.Rollback(context.Background())
+	}()
+
+	fn(tx)
 }
 
 func (p *PgDb) Name() string {
@@ -40,7 +137,7 @@ func (p *PgDb) Dsn() string {
 func (p *PgDb) Insert(t testing.TB, table string, data ...map[string]any) {
 	t.Helper()
 
-	conn := p.connect(t, p.dsn)
+	conn := p.queryer(t)
 
 	for _, entry := range data {
 		args := make([]any, 0, len(entry))
@@ -67,7 +164,7 @@ func (p *PgDb) Insert(t testing.TB, table string, data ...map[string]any) {
 }
 
 func (p *PgDb) QueryValue(t testing.TB, sql string, into any, args ...any) {
-	conn := p.connect(t, p.dsn)
+	conn := p.queryer(t)
 
 	row := conn.QueryRow(context.Background(), sql, args...)
 
@@ -80,7 +177,7 @@ func (p *PgDb) QueryValue(t testing.TB, sql string, into any, args ...any) {
 }
 
 func (p *PgDb) QueryRow(t testing.TB, sql string, args ...any) func(into ...any) {
-	conn := p.connect(t, p.dsn)
+	conn := p.queryer(t)
 
 	row := conn.QueryRow(context.Background(), sql, args...)
 
@@ -95,19 +192,30 @@ func (p *PgDb) QueryRow(t testing.TB, sql string, args ...any) func(into ...any)
 }
 
 func (p *PgDb) Exec(t testing.TB, sql string, args ...any) ExecResult {
-	c, err := p.connect(t, p.dsn).Exec(context.Background(), sql, args...)
+	c, err := p.queryer(t).Exec(context.Background(), sql, args...)
 	must(t, err)
 
 	return ExecResult{RowsAffected: c.RowsAffected()}
 }
 
 func (p *PgDb) Drop(t testing.TB) {
-	// Close our open connections.
-	for _, conn := range p.conns {
-		_ = conn.Close(context.Background())
+	if p.tx != nil {
+		_ = p.tx.Rollback(context.Background())
+		if p.txConn != nil {
+			_ = p.txConn.Close(context.Background())
+		}
+		return
 	}
 
-	root := p.connect(t, p.rootDsn)
+	p.poolMu.Lock()
+	if p.pool != nil {
+		p.pool.Close()
+		p.pool = nil
+	}
+	p.poolMu.Unlock()
+
+	root, err := pgx.Connect(context.Background(), p.rootDsn)
+	must(t, err)
 	defer root.Close(context.Background())
 
 	// Forcibly close any remaining connections
@@ -116,30 +224,11 @@ SELECT pg_terminate_backend(pg_stat_activity.pid)
 FROM pg_stat_activity
 WHERE pg_stat_activity.datname = '%s'`
 
-	_, err := root.Exec(context.Background(), fmt.Sprintf(closeConns, verifyPgDbName(t, p.name)))
+	_, err = root.Exec(context.Background(), fmt.Sprintf(closeConns, verifyPgDbName(t, p.name)))
 	must(t, err)
 
 	_, err = root.Exec(context.Background(), fmt.Sprintf("DROP DATABASE IF EXISTS \"%s\"", verifyPgDbName(t, p.name)))
 	must(t, err)
-
-	p.conns = nil
-}
-
-func (p *PgDb) connect(t testing.TB, dsn string) *pgx.Conn {
-	if p.conns == nil {
-		p.conns = make(map[string]*pgx.Conn)
-	}
-
-	existing, exists := p.conns[dsn]
-
-	if exists {
-		return existing
-	}
-
-	conn, err := pgx.Connect(context.Background(), dsn)
-	must(t, err)
-	p.conns[dsn] = conn
-	return conn
 }
 
 type pgInitializer struct{}
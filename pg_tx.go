@@ -0,0 +1,124 @@
+package testdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// sharedPgDsnCache holds the DSN of the long-lived, migrated database used
+// by NewPgTx, keyed by dsn and migration hash, so it's created at most once
+// per process no matter how many tests call NewPgTx. dsn must be part of
+// the key alongside the migration hash: two different Postgres targets can
+// easily share a migration hash (the same migrations dir used against both),
+// and without dsn in the key the second target would silently reuse the
+// first's shared database DSN instead of getting its own.
+var sharedPgDsnCache sync.Map // map[string]string
+
+// NewPgTx initialises a Db backed by a single long-lived Postgres database
+// shared across every test in the process, scoping each test to its own
+// transaction that's rolled back on t.Cleanup instead of creating (or
+// template-copying) a fresh database per test. This trades isolation
+// between connections for roughly an order of magnitude less overhead than
+// NewPg, and suits suites with thousands of fast, table-level tests.
+//
+// Application code under test must accept a pgx.Tx (or other DBTX-style
+// interface) rather than opening its own connection, so that it runs inside
+// the same transaction as the test.
+//
+// provide a nil migrator to disable any migrations and use a blank database
+// instead.
+func NewPgTx(t testing.TB, dsn string, migrator Migrator) Db {
+	sharedDsn := sharedPgDsn(t, dsn, migrator)
+
+	conn, err := pgx.Connect(context.Background(), sharedDsn)
+	must(t, err)
+
+	tx, err := conn.Begin(context.Background())
+	must(t, err)
+
+	db := &PgDb{
+		dsn:    sharedDsn,
+		tx:     tx,
+		txConn: conn,
+	}
+
+	t.Cleanup(func() {
+		db.Drop(t)
+	})
+
+	return db
+}
+
+// sharedPgDsn returns the DSN of the long-lived migrated database used by
+// NewPgTx, creating and migrating it under the same advisory-lock +
+// migration-hash template flow as New, but only the first time it's needed
+// in this process.
+func sharedPgDsn(t testing.TB, dsn string, migrator Migrator) string {
+	migrationHash := "none"
+	if migrator != nil {
+		migrationHash = migrator.Hash(t)
+	}
+	cacheKey := sharedPgDsnCacheKey(dsn, migrationHash)
+
+	if cached, ok := sharedPgDsnCache.Load(cacheKey); ok {
+		return cached.(string)
+	}
+
+	init := &pgInitializer{}
+
+	root := init.Connect(t, dsn)
+	defer root.Close(context.Background())
+
+	name := fmt.Sprintf("test_shared_%s", migrationHash)
+
+	init.Lock(t, root, name)
+	defer init.Unlock(t, root, name)
+
+	if !init.Exists(t, root, name) {
+		init.Create(t, root, name)
+		if migrator != nil {
+			migrator.Migrate(t, init.NewDsn(t, dsn, name))
+		}
+	}
+
+	sharedDsn := init.NewDsn(t, dsn, name)
+	sharedPgDsnCache.Store(cacheKey, sharedDsn)
+
+	return sharedDsn
+}
+
+// sharedPgDsnCacheKey builds the sharedPgDsnCache key for a given root dsn
+// and migration hash; dsn must be included so that two different Postgres
+// targets sharing a migration hash don't collide on the same cache entry.
+func sharedPgDsnCacheKey(dsn, migrationHash string) string {
+	return dsn + "\x00" + migrationHash
+}
+
+// Savepoint opens a Postgres SAVEPOINT within the shared transaction behind
+// db, scoping whatever runs until t's cleanup to its own rollback point on
+// top of it. Call this at the top of a t.Run subtest so nested subtests
+// don't see each other's writes. db must have been created by NewPgTx.
+func Savepoint(t testing.TB, db Db) {
+	t.Helper()
+
+	p, ok := db.(*PgDb)
+	if !ok || p.tx == nil {
+		ErrorHandler(t, fmt.Errorf("testdb: Savepoint requires a Db created by NewPgTx"))
+		return
+	}
+
+	name := fmt.Sprintf("sp_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
+
+	_, err := p.tx.Exec(context.Background(), fmt.Sprintf("SAVEPOINT %s", name))
+	must(t, err)
+
+	t.Cleanup(func() {
+		_, _ = p.tx.Exec(context.Background(), fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", name))
+	})
+}
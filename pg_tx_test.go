@@ -0,0 +1,12 @@
+package testdb
+
+import "testing"
+
+func TestSharedPgDsnCacheKey_distinguishesDsn(t *testing.T) {
+	a := sharedPgDsnCacheKey("postgres://host-a/db?sslmode=disable", "abc123")
+	b := sharedPgDsnCacheKey("postgres://host-b/db?sslmode=disable", "abc123")
+
+	if a == b {
+		t.Fatalf("two different dsns with the same migration hash must not share a cache key, got %q for both", a)
+	}
+}
@@ -0,0 +1,68 @@
+package testdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// SqlFileSeeder returns a Seeder that seeds the template database by
+// executing the contents of the .sql file at path against it, once.
+func SqlFileSeeder(path string) Seeder {
+	return &sqlFileSeeder{path: path}
+}
+
+type sqlFileSeeder struct {
+	path string
+}
+
+func (s *sqlFileSeeder) Hash(t testing.TB) string {
+	b, err := os.ReadFile(s.path)
+	must(t, err)
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *sqlFileSeeder) Seed(t testing.TB, dsn string) {
+	b, err := os.ReadFile(s.path)
+	must(t, err)
+
+	conn, err := pgx.Connect(context.Background(), dsn)
+	must(t, err)
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(context.Background(), string(b))
+	must(t, err)
+}
+
+// FuncSeeder returns a Seeder that seeds the template database by running
+// fn against a *pgx.Conn connected to it. Go gives us no way to detect
+// whether fn's body has changed (a function's symbol name and address stay
+// the same across edits, and reflect can't see its source), so hash must be
+// supplied by the caller and bumped whenever fn's behaviour changes, the
+// same way Migrator.Hash is derived from migration file contents.
+func FuncSeeder(hash string, fn func(context.Context, *pgx.Conn) error) Seeder {
+	return &funcSeeder{hash: hash, fn: fn}
+}
+
+type funcSeeder struct {
+	hash string
+	fn   func(context.Context, *pgx.Conn) error
+}
+
+func (s *funcSeeder) Hash(t testing.TB) string {
+	return s.hash
+}
+
+func (s *funcSeeder) Seed(t testing.TB, dsn string) {
+	conn, err := pgx.Connect(context.Background(), dsn)
+	must(t, err)
+	defer conn.Close(context.Background())
+
+	must(t, s.fn(context.Background(), conn))
+}
@@ -0,0 +1,367 @@
+package testdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Dialect captures the small handful of SQL differences testdb needs in
+// order to build queries against a database/sql driver: how positional
+// parameters and identifiers are written.
+type Dialect struct {
+	// Placeholder returns the positional parameter marker for the i'th
+	// (1-indexed) argument, e.g. "$1" for postgres, "?" for mysql.
+	Placeholder func(i int) string
+	// Quote quotes ident as an identifier (database/table/column name),
+	// e.g. `"ident"` for postgres, "`ident`" for mysql.
+	Quote func(ident string) string
+}
+
+// PostgresDialect is the Dialect for Postgres-family drivers (lib/pq,
+// pgx/stdlib), for use with NewSQL/SQLInitializer.
+var PostgresDialect = Dialect{
+	Placeholder: func(i int) string { return fmt.Sprintf("$%d", i) },
+	Quote:       func(ident string) string { return fmt.Sprintf("%q", ident) },
+}
+
+// MySQLDialect is the Dialect for github.com/go-sql-driver/mysql, for use
+// with NewSQL/SQLInitializer.
+var MySQLDialect = Dialect{
+	Placeholder: func(i int) string { return "?" },
+	Quote:       func(ident string) string { return fmt.Sprintf("`%s`", ident) },
+}
+
+// sqlDb is a database/sql-backed implementation of Db, shared by every
+// Initializer that talks to its database purely through database/sql
+// (SQLInitializer, mysqlInitializer) rather than a driver-native client.
+// PgDb stays a separate, pgx-native implementation for users who want pgx
+// features (COPY, LISTEN/NOTIFY).
+type sqlDb struct {
+	name    string
+	dsn     string
+	rootDsn string
+	driver  string
+	dialect Dialect
+
+	mu sync.Mutex
+	db *sql.DB
+}
+
+func (s *sqlDb) Name() string {
+	return s.name
+}
+
+func (s *sqlDb) Dsn() string {
+	return s.dsn
+}
+
+func (s *sqlDb) Insert(t testing.TB, table string, data ...map[string]any) {
+	t.Helper()
+
+	conn := s.connect(t)
+
+	for _, entry := range data {
+		args := make([]any, 0, len(entry))
+		cols := make([]string, 0, len(entry))
+		placeholders := make([]string, 0, len(entry))
+		i := 1
+		for name, val := range entry {
+			args = append(args, val)
+			cols = append(cols, name)
+			placeholders = append(placeholders, s.dialect.Placeholder(i))
+			i++
+		}
+
+		query := fmt.Sprintf(
+			"INSERT INTO %s(%s) VALUES(%s)",
+			table,
+			strings.Join(cols, ","),
+			strings.Join(placeholders, ","),
+		)
+
+		_, err := conn.ExecContext(context.Background(), query, args...)
+		must(t, err)
+	}
+}
+
+func (s *sqlDb) QueryValue(t testing.TB, query string, into any, args ...any) {
+	row := s.connect(t).QueryRowContext(context.Background(), query, args...)
+
+	err := row.Scan(into)
+	if errors.Is(err, sql.ErrNoRows) {
+		must(t, err, "test database query for a single value returned 0 rows")
+	} else {
+		must(t, err)
+	}
+}
+
+func (s *sqlDb) QueryRow(t testing.TB, query string, args ...any) func(into ...any) {
+	row := s.connect(t).QueryRowContext(context.Background(), query, args...)
+
+	return func(into ...any) {
+		err := row.Scan(into...)
+		if errors.Is(err, sql.ErrNoRows) {
+			must(t, err, "test database query for a single row returned 0 rows")
+		} else {
+			must(t, err)
+		}
+	}
+}
+
+func (s *sqlDb) Exec(t testing.TB, query string, args ...any) ExecResult {
+	res, err := s.connect(t).ExecContext(context.Background(), query, args...)
+	must(t, err)
+
+	affected, err := res.RowsAffected()
+	must(t, err)
+
+	return ExecResult{RowsAffected: affected}
+}
+
+func (s *sqlDb) Drop(t testing.TB) {
+	s.mu.Lock()
+	if s.db != nil {
+		_ = s.db.Close()
+		s.db = nil
+	}
+	s.mu.Unlock()
+
+	root, err := sql.Open(s.driver, s.rootDsn)
+	must(t, err)
+	defer root.Close()
+
+	_, err = root.ExecContext(context.Background(), fmt.Sprintf("DROP DATABASE IF EXISTS %s", s.dialect.Quote(s.name)))
+	must(t, err)
+}
+
+// connect is called from every query method, including by concurrent
+// t.Parallel() subtests sharing this Db, so lazy creation of s.db is guarded
+// by mu to avoid both a data race on it and leaking whichever *sql.DB loses
+// the race (mirrors PgDb.Pool's poolMu).
+func (s *sqlDb) connect(t testing.TB) *sql.DB {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		db, err := sql.Open(s.driver, s.dsn)
+		must(t, err)
+		s.db = db
+	}
+
+	return s.db
+}
+
+// sqlInitLocks backs SQLInitializer.Lock/Unlock: a process-local mutex per
+// template name, as permitted by Initializer.Lock's doc ("this may be done
+// with stdlib sync stuff"). This is enough to serialize template creation
+// within a single `go test` run, which is the common case for a generic,
+// driver-agnostic Initializer; a driver-native Initializer (pgInitializer,
+// mysqlInitializer) uses a real database-level lock to also cover
+// concurrent processes.
+var sqlInitLocks sync.Map // map[string]*sync.Mutex
+
+// sqlSchemaCache holds the DDL dumped for a template database, keyed by
+// templateName, for engines that don't support CREATE DATABASE ... TEMPLATE
+// (see SQLInitializer.SupportsCreateFromTemplate).
+var sqlSchemaCache sync.Map // map[string][]string
+
+// SQLInitializer implements Initializer[*sql.DB] for any driver registered
+// with database/sql (e.g. lib/pq, pgx/stdlib, go-sql-driver/mysql), so
+// users aren't required to depend on a driver-native package like pgx just
+// to use testdb. Use NewSQL to build a Db from one.
+type SQLInitializer struct {
+	// DriverName is passed to sql.Open, e.g. "postgres", "pgx", "mysql".
+	DriverName string
+	// Dialect describes this driver's placeholder and identifier-quoting
+	// conventions.
+	Dialect Dialect
+	// SupportsCreateFromTemplate reports whether CREATE DATABASE ...
+	// TEMPLATE is supported (true for Postgres-family drivers). When
+	// false, CreateFromTemplate falls back to dumping the template's
+	// schema via information_schema and SHOW CREATE TABLE, and replaying
+	// it into the new database, as MySQL requires.
+	SupportsCreateFromTemplate bool
+
+	dsn string
+}
+
+func (s *SQLInitializer) Connect(t testing.TB, dsn string) *sql.DB {
+	s.dsn = dsn
+
+	db, err := sql.Open(s.DriverName, dsn)
+	must(t, err)
+	return db
+}
+
+func (s *SQLInitializer) Lock(t testing.TB, conn *sql.DB, name string) {
+	v, _ := sqlInitLocks.LoadOrStore(name, &sync.Mutex{})
+	v.(*sync.Mutex).Lock()
+}
+
+func (s *SQLInitializer) Unlock(t testing.TB, conn *sql.DB, name string) {
+	v, ok := sqlInitLocks.Load(name)
+	if !ok {
+		return
+	}
+	v.(*sync.Mutex).Unlock()
+}
+
+func (s *SQLInitializer) Exists(t testing.TB, conn *sql.DB, name string) bool {
+	row := conn.QueryRowContext(context.Background(), s.existsQuery(), name)
+
+	var got string
+	err := row.Scan(&got)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+	must(t, err)
+
+	return true
+}
+
+// existsQuery returns the query Exists runs to check whether a
+// database/schema of a given name already exists. Postgres and MySQL differ
+// here: information_schema.schemata only lists schemas within the database
+// conn is connected to, never sibling databases, so on Postgres (where every
+// test/template database is its own separate database, unlike MySQL's
+// schemas-within-one-database model) it can never see a template/test
+// database created alongside it. SupportsCreateFromTemplate doubles as our
+// signal for "is this Postgres", same as CreateFromTemplate already uses it.
+func (s *SQLInitializer) existsQuery() string {
+	if s.SupportsCreateFromTemplate {
+		return fmt.Sprintf("SELECT datname FROM pg_database WHERE datname = %s", s.Dialect.Placeholder(1))
+	}
+
+	return fmt.Sprintf("SELECT schema_name FROM information_schema.schemata WHERE schema_name = %s", s.Dialect.Placeholder(1))
+}
+
+func (s *SQLInitializer) Create(t testing.TB, conn *sql.DB, name string) {
+	_, err := conn.ExecContext(context.Background(), fmt.Sprintf("CREATE DATABASE %s", s.Dialect.Quote(name)))
+	must(t, err)
+}
+
+func (s *SQLInitializer) CreateFromTemplate(t testing.TB, conn *sql.DB, template, name string) {
+	if s.SupportsCreateFromTemplate {
+		_, err := conn.ExecContext(context.Background(), fmt.Sprintf(
+			"CREATE DATABASE %s TEMPLATE %s", s.Dialect.Quote(name), s.Dialect.Quote(template)))
+		must(t, err)
+		return
+	}
+
+	ddl := s.schemaFor(t, conn, template)
+
+	s.Create(t, conn, name)
+
+	targetDsn := s.NewDsn(t, s.dsn, name)
+	target, err := sql.Open(s.DriverName, targetDsn)
+	must(t, err)
+	defer target.Close()
+
+	for _, stmt := range ddl {
+		_, err := target.ExecContext(context.Background(), stmt)
+		must(t, err)
+	}
+}
+
+func (s *SQLInitializer) schemaFor(t testing.TB, conn *sql.DB, template string) []string {
+	if cached, ok := sqlSchemaCache.Load(template); ok {
+		return cached.([]string)
+	}
+
+	rows, err := conn.QueryContext(context.Background(),
+		fmt.Sprintf("SELECT table_name FROM information_schema.tables WHERE table_schema = %s", s.Dialect.Placeholder(1)),
+		template)
+	must(t, err)
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		must(t, rows.Scan(&name))
+		tables = append(tables, name)
+	}
+	must(t, rows.Err())
+	_ = rows.Close()
+
+	ddl := make([]string, 0, len(tables))
+	for _, table := range tables {
+		var tbl, create string
+		row := conn.QueryRowContext(context.Background(),
+			fmt.Sprintf("SHOW CREATE TABLE %s.%s", s.Dialect.Quote(template), s.Dialect.Quote(table)))
+		must(t, row.Scan(&tbl, &create))
+		ddl = append(ddl, create)
+	}
+
+	sqlSchemaCache.Store(template, ddl)
+	return ddl
+}
+
+func (s *SQLInitializer) NewDsn(t testing.TB, base string, newName string) string {
+	r := regexp.MustCompile(`/\w+\?`)
+	if r.MatchString(base) {
+		return r.ReplaceAllString(base, fmt.Sprintf("/%s?", newName))
+	}
+
+	r = regexp.MustCompile(`/\w+$`)
+	if r.MatchString(base) {
+		return r.ReplaceAllString(base, "/"+newName)
+	}
+
+	ErrorHandler(t, fmt.Errorf("invalid DSN provided, cannot find database name in `%s`", base))
+	return base
+}
+
+func (s *SQLInitializer) NewDb(t testing.TB, rootDsn, dsn string) Db {
+	return &sqlDb{
+		name:    dbNameFromDsn(t, dsn),
+		dsn:     dsn,
+		rootDsn: rootDsn,
+		driver:  s.DriverName,
+		dialect: s.Dialect,
+	}
+}
+
+func (s *SQLInitializer) Remove(t testing.TB, conn *sql.DB, name string) {
+	_, err := conn.ExecContext(context.Background(), fmt.Sprintf("DROP DATABASE IF EXISTS %s", s.Dialect.Quote(name)))
+	must(t, err)
+}
+
+// NewSQL initialises a new test database using a plain database/sql driver
+// (driverName, already registered by that driver package's import) instead
+// of a driver-native client. This lets users of lib/pq, pgx/stdlib,
+// go-sql-driver/mysql or similar share one code path rather than depending
+// on pgx directly.
+//
+// initializer is only read (for its Dialect and SupportsCreateFromTemplate),
+// never mutated, so one *SQLInitializer can safely be shared and reused
+// across many (including concurrent) tests; NewSQL takes a per-call copy
+// internally to hold this call's driverName/dsn rather than writing them
+// onto the caller's instance.
+//
+// provide a nil migrator to disable any migrations and return a blank
+// database instead.
+func NewSQL(t testing.TB, driverName, dsn string, initializer *SQLInitializer, migrator Migrator) Db {
+	local := *initializer
+	local.DriverName = driverName
+
+	return New[*sql.DB](t, dsn, &local, migrator)
+}
+
+var dbNameRegex = regexp.MustCompile(`/(\w+)(\?|$)`)
+
+// dbNameFromDsn extracts the database name from a URL-style DSN
+// (scheme://host/dbname?params), as used by lib/pq and pgx/stdlib.
+func dbNameFromDsn(t testing.TB, dsn string) string {
+	m := dbNameRegex.FindStringSubmatch(dsn)
+	if m == nil {
+		ErrorHandler(t, fmt.Errorf("invalid DSN provided, cannot find database name in `%s`", dsn))
+		return ""
+	}
+
+	return m[1]
+}
@@ -0,0 +1,15 @@
+package testdb
+
+import "testing"
+
+func TestSQLInitializer_existsQuery(t *testing.T) {
+	pg := &SQLInitializer{Dialect: PostgresDialect, SupportsCreateFromTemplate: true}
+	if got := pg.existsQuery(); got != `SELECT datname FROM pg_database WHERE datname = $1` {
+		t.Fatalf("postgres existsQuery should check pg_database, got: %s", got)
+	}
+
+	mysql := &SQLInitializer{Dialect: MySQLDialect, SupportsCreateFromTemplate: false}
+	if got := mysql.existsQuery(); got != "SELECT schema_name FROM information_schema.schemata WHERE schema_name = ?" {
+		t.Fatalf("mysql existsQuery should check information_schema.schemata, got: %s", got)
+	}
+}
@@ -86,6 +86,20 @@ var ErrorHandler = func(t testing.TB, err error, extra ...any) {
 	t.Fatal(append([]any{"testdb initialisation failed", err}, extra...))
 }
 
+// Seeder pre-populates a template database with fixture data, so every test
+// starts from the same dataset without paying the cost of inserting it per
+// test. Seed runs once per template, right after migrations, inside the
+// same lock; CREATE DATABASE ... TEMPLATE then copies the seeded rows into
+// every test database for free.
+type Seeder interface {
+	// Hash works as per Migrator.Hash, but for seed data. It's folded into
+	// the template name alongside the migration hash, so changing the seed
+	// data produces a new template.
+	Hash(t testing.TB) string
+	// Seed populates the database at dsn with fixture data.
+	Seed(t testing.TB, dsn string)
+}
+
 // New initialises a new test database at the database indicated by dsn.
 // dsn must be a valid connection that has permission to create new databases.
 // Returns the Db handle representing a fully migrated, isolated database ready
@@ -94,10 +108,23 @@ var ErrorHandler = func(t testing.TB, err error, extra ...any) {
 // You may want to use a ready-provided constructor, such as NewPg. This is exposed
 // for custom initializers if you're using a database that isn't supported.
 func New[Conn any](t testing.TB, dsn string, h Initializer[Conn], m Migrator) Db {
+	return newDb[Conn](t, dsn, h, m, nil)
+}
+
+// NewWithSeed is New, but also pre-populates the template database with s
+// once it's been migrated. See Seeder.
+func NewWithSeed[Conn any](t testing.TB, dsn string, h Initializer[Conn], m Migrator, s Seeder) Db {
+	return newDb[Conn](t, dsn, h, m, s)
+}
+
+func newDb[Conn any](t testing.TB, dsn string, h Initializer[Conn], m Migrator, s Seeder) Db {
 	root := h.Connect(t, dsn)
 
 	migrationHash := m.Hash(t)
 	templateName := fmt.Sprintf("test_template_%s", migrationHash)
+	if s != nil {
+		templateName = fmt.Sprintf("%s_%s", templateName, s.Hash(t))
+	}
 
 	h.Lock(t, root, templateName)
 
@@ -106,15 +133,21 @@ func New[Conn any](t testing.TB, dsn string, h Initializer[Conn], m Migrator) Db
 
 		done := false
 		// Due to our halting error handling, here we add an explicit check
-		// to see if the migration has applied. If not, remove the template
-		// DB as it'll be corrupt/bad.
+		// to see if the migration (and seed) have applied. If not, remove
+		// the template DB as it'll be corrupt/bad.
 		t.Cleanup(func() {
 			if !done {
 				h.Remove(t, root, templateName)
 			}
 		})
 
-		m.Migrate(t, h.NewDsn(t, dsn, templateName))
+		templateDsn := h.NewDsn(t, dsn, templateName)
+		m.Migrate(t, templateDsn)
+
+		if s != nil {
+			s.Seed(t, templateDsn)
+		}
+
 		done = true
 	}
 